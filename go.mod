@@ -0,0 +1,3 @@
+module github.com/pgvanniekerk/ezWorker
+
+go 1.21
@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+)
+
+// BatchStream groups the values read from in into slices of up to size
+// values. A batch is emitted when it is full or when maxWait has elapsed
+// since its first value arrived, whichever comes first. A maxWait of zero or
+// less disables the time limit, and a size below one is treated as one.
+//
+// When in is closed any partial batch is emitted before the returned channel
+// is closed. When ctx is cancelled the partial batch is discarded.
+func BatchStream[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	out := make(chan []T)
+	size = max(size, 1)
+
+	go func() {
+		defer close(out)
+
+		var (
+			batch []T
+			timer *time.Timer
+			// expired stays nil while no time limit is pending, so its
+			// select case never fires.
+			expired <-chan time.Time
+		)
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, expired = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- batch:
+				batch = nil
+				return true
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-expired:
+				if !flush() {
+					return
+				}
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					expired = timer.C
+				}
+				if len(batch) >= size && !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// receive returns the next batch from out, failing the test if none arrives
+// within a second.
+func receive(t *testing.T, out <-chan []int) []int {
+	t.Helper()
+
+	select {
+	case b, ok := <-out:
+		if !ok {
+			t.Fatal("channel closed, want a batch")
+		}
+		return b
+	case <-time.After(time.Second):
+		t.Fatal("no batch received")
+	}
+
+	return nil
+}
+
+func TestBatchStreamClose(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		size int
+		want [][]int
+	}{
+		{name: "empty input emits nothing", in: nil, size: 2, want: nil},
+		{name: "full batches", in: []int{1, 2, 3, 4}, size: 2, want: [][]int{{1, 2}, {3, 4}}},
+		{name: "partial batch flushed on close", in: []int{1, 2, 3}, size: 2, want: [][]int{{1, 2}, {3}}},
+		{name: "zero size acts as one", in: []int{1, 2, 3}, size: 0, want: [][]int{{1}, {2}, {3}}},
+		{name: "negative size acts as one", in: []int{1, 2}, size: -3, want: [][]int{{1}, {2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := drain(t, BatchStream(context.Background(), source(tt.in...), tt.size, 0))
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]int]) {
+				t.Errorf("batches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchStreamFullBeforeMaxWait(t *testing.T) {
+	in := make(chan int)
+	defer close(in)
+	out := BatchStream(context.Background(), in, 2, time.Hour)
+
+	in <- 1
+	in <- 2
+
+	if got := receive(t, out); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("batch = %v, want [1 2]", got)
+	}
+}
+
+func TestBatchStreamMaxWait(t *testing.T) {
+	const maxWait = 20 * time.Millisecond
+
+	in := make(chan int)
+	defer close(in)
+	out := BatchStream(context.Background(), in, 10, maxWait)
+
+	// Each partial batch is emitted by its own timer, started when the
+	// batch's first value arrives.
+	for _, want := range [][]int{{1, 2}, {3}} {
+		start := time.Now()
+		for _, v := range want {
+			in <- v
+		}
+
+		got := receive(t, out)
+		if !slices.Equal(got, want) {
+			t.Errorf("batch = %v, want %v", got, want)
+		}
+		if elapsed := time.Since(start); elapsed < maxWait {
+			t.Errorf("batch emitted after %v, want at least %v", elapsed, maxWait)
+		}
+	}
+}
+
+func TestBatchStreamNoMaxWait(t *testing.T) {
+	for _, maxWait := range []time.Duration{0, -time.Second} {
+		in := make(chan int)
+		out := BatchStream(context.Background(), in, 10, maxWait)
+
+		in <- 1
+
+		select {
+		case b := <-out:
+			t.Fatalf("maxWait %v: batch %v emitted, want none before close", maxWait, b)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(in)
+		if got := drain(t, out); !slices.EqualFunc(got, [][]int{{1}}, slices.Equal[[]int]) {
+			t.Errorf("maxWait %v: batches = %v, want [[1]]", maxWait, got)
+		}
+	}
+}
+
+func TestBatchStreamCancelDiscardsPartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The input stays open, so only cancellation can close the output.
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	out := BatchStream(ctx, in, 10, 0)
+
+	for len(in) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if got := drain(t, out); len(got) != 0 {
+		t.Errorf("batches = %v, want the partial batch discarded", got)
+	}
+}
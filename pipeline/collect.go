@@ -0,0 +1,42 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+)
+
+// CollectOutputs reads from ch until it has limit values, ch is closed, or
+// timeout elapses, and returns the values read. A limit of zero or less
+// collects without a count limit, and a timeout of zero or less collects
+// without a deadline, so at least one of limit or a closing ch must end the
+// collection.
+func CollectOutputs[O any](ch <-chan O, limit int, timeout time.Duration) []O {
+	if timeout <= 0 {
+		return CollectOutputsContext(context.Background(), ch, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return CollectOutputsContext(ctx, ch, limit)
+}
+
+// CollectOutputsContext is like CollectOutputs but stops when ctx is done
+// instead of after a fixed timeout.
+func CollectOutputsContext[O any](ctx context.Context, ch <-chan O, limit int) []O {
+	var out []O
+
+	for limit <= 0 || len(out) < limit {
+		select {
+		case <-ctx.Done():
+			return out
+		case v, ok := <-ch:
+			if !ok {
+				return out
+			}
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
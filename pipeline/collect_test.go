@@ -0,0 +1,98 @@
+package pipeline
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCollectOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ch      func() <-chan int
+		limit   int
+		timeout time.Duration
+		want    []int
+	}{
+		{name: "stops at limit", ch: func() <-chan int { return source(1, 2, 3) }, limit: 2, timeout: time.Second, want: []int{1, 2}},
+		{name: "stops when closed", ch: func() <-chan int { return source(1, 2, 3) }, limit: 5, timeout: time.Second, want: []int{1, 2, 3}},
+		{name: "non-positive limit reads until closed", ch: func() <-chan int { return source(1, 2, 3) }, limit: 0, timeout: time.Second, want: []int{1, 2, 3}},
+		{name: "stops at timeout", ch: func() <-chan int { return make(chan int) }, limit: 5, timeout: 20 * time.Millisecond, want: nil},
+		{
+			name: "timeout keeps values read so far",
+			ch: func() <-chan int {
+				ch := make(chan int, 2)
+				ch <- 1
+				ch <- 2
+				return ch
+			},
+			limit:   5,
+			timeout: 20 * time.Millisecond,
+			want:    []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CollectOutputs(tt.ch(), tt.limit, tt.timeout)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("collected = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectOutputsNoTimeout(t *testing.T) {
+	for _, timeout := range []time.Duration{0, -time.Second} {
+		// Repeated calls on a ready channel must behave the same every time
+		// rather than racing an already expired deadline.
+		ch := make(chan int, 20)
+		for i := 0; i < 20; i++ {
+			ch <- i
+		}
+
+		for i := 0; i < 5; i++ {
+			got := CollectOutputs(ch, 4, timeout)
+			want := []int{i * 4, i*4 + 1, i*4 + 2, i*4 + 3}
+			if !slices.Equal(got, want) {
+				t.Fatalf("timeout %v, call %d: collected = %v, want %v", timeout, i, got, want)
+			}
+		}
+	}
+
+	// Without a deadline, a slow producer is waited for.
+	ch := make(chan int)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		ch <- 1
+		close(ch)
+	}()
+	if got := CollectOutputs(ch, 0, 0); !slices.Equal(got, []int{1}) {
+		t.Errorf("collected = %v, want [1]", got)
+	}
+}
+
+func TestCollectOutputsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan int, 1)
+	ch <- 1
+
+	done := make(chan []int)
+	go func() { done <- CollectOutputsContext(ctx, ch, 0) }()
+
+	for len(ch) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case got := <-done:
+		if !slices.Equal(got, []int{1}) {
+			t.Errorf("collected = %v, want [1]", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CollectOutputsContext did not return after cancel")
+	}
+}
@@ -0,0 +1,44 @@
+package pipeline
+
+import "context"
+
+// Partition splits in into two channels: matched receives the values for
+// which pred returns true and unmatched receives the rest. Both channels are
+// closed when in is closed or ctx is cancelled.
+//
+// Partition delivers values one at a time from a single goroutine, so a
+// consumer that stops reading from either channel stalls the other as well.
+// Drain both channels, or give the consumers their own buffering.
+func Partition[T any](ctx context.Context, in <-chan T, pred func(T) bool) (matched <-chan T, unmatched <-chan T) {
+	yes := make(chan T)
+	no := make(chan T)
+
+	go func() {
+		defer close(yes)
+		defer close(no)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				out := no
+				if pred(v) {
+					out = yes
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return yes, no
+}
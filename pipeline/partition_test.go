@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPartition(t *testing.T) {
+	even := func(v int) bool { return v%2 == 0 }
+
+	tests := []struct {
+		name          string
+		in            []int
+		wantMatched   []int
+		wantUnmatched []int
+	}{
+		{name: "empty input closes both", in: nil, wantMatched: nil, wantUnmatched: nil},
+		{name: "routes by predicate", in: []int{1, 2, 3, 4, 5}, wantMatched: []int{2, 4}, wantUnmatched: []int{1, 3, 5}},
+		{name: "all matched", in: []int{2, 4}, wantMatched: []int{2, 4}, wantUnmatched: nil},
+		{name: "all unmatched", in: []int{1, 3}, wantMatched: nil, wantUnmatched: []int{1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, unmatched := Partition(context.Background(), source(tt.in...), even)
+
+			// Both outputs must be read concurrently, since a stalled
+			// consumer on either blocks the partitioner.
+			var wg sync.WaitGroup
+			var gotMatched, gotUnmatched []int
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for v := range matched {
+					gotMatched = append(gotMatched, v)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for v := range unmatched {
+					gotUnmatched = append(gotUnmatched, v)
+				}
+			}()
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("outputs were not closed")
+			}
+
+			if !slices.Equal(gotMatched, tt.wantMatched) {
+				t.Errorf("matched = %v, want %v", gotMatched, tt.wantMatched)
+			}
+			if !slices.Equal(gotUnmatched, tt.wantUnmatched) {
+				t.Errorf("unmatched = %v, want %v", gotUnmatched, tt.wantUnmatched)
+			}
+		})
+	}
+}
+
+func TestPartitionCancel(t *testing.T) {
+	tests := []struct {
+		name   string
+		queued []int
+	}{
+		{name: "blocked on input", queued: nil},
+		{name: "blocked on output send", queued: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			// The input stays open, so only cancellation can close the outputs.
+			in := make(chan int, len(tt.queued))
+			for _, v := range tt.queued {
+				in <- v
+			}
+			matched, unmatched := Partition(ctx, in, func(v int) bool { return v%2 == 0 })
+
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+
+			waitClosed(t, matched)
+			waitClosed(t, unmatched)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Package pipeline provides generic combinators for composing channel
+// streams, such as an executor's output channel, into processing chains.
+//
+// Combinators that return a channel take a context as their first argument.
+// Cancelling the context stops the combinator's goroutine and closes its
+// output channel; otherwise the output channel is closed once the input
+// channel has been closed and fully consumed.
+package pipeline
+
+import "context"
+
+// Map returns a channel that receives f applied to each value read from in.
+//
+// If f returns an error the value is dropped and the error is sent to errc.
+// A nil errc discards such errors. Sends on both the returned channel and
+// errc are abandoned when ctx is cancelled.
+func Map[O, O2 any](ctx context.Context, in <-chan O, f func(O) (O2, error), errc chan<- error) <-chan O2 {
+	out := make(chan O2)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				mapped, err := f(v)
+				if err != nil {
+					if errc == nil {
+						continue
+					}
+					select {
+					case <-ctx.Done():
+						return
+					case errc <- err:
+					}
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- mapped:
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Filter returns a channel that receives only the values read from in for
+// which pred returns true.
+func Filter[O any](ctx context.Context, in <-chan O, pred func(O) bool) <-chan O {
+	out := make(chan O)
+
+	go func() {
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				if !pred(v) {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
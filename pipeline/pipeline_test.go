@@ -0,0 +1,201 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// source returns a closed, buffered channel holding vs.
+func source[T any](vs ...T) <-chan T {
+	ch := make(chan T, len(vs))
+	for _, v := range vs {
+		ch <- v
+	}
+	close(ch)
+
+	return ch
+}
+
+// drain reads ch until it is closed, failing the test if that takes longer
+// than a second.
+func drain[T any](t *testing.T, ch <-chan T) []T {
+	t.Helper()
+
+	var got []T
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-deadline:
+			t.Fatal("channel was not closed")
+		}
+	}
+}
+
+// waitClosed fails the test if ch does not close within a second. Values
+// received before the close are discarded.
+func waitClosed[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("channel was not closed")
+		}
+	}
+}
+
+var errOdd = errors.New("odd")
+
+func TestMap(t *testing.T) {
+	double := func(v int) (int, error) {
+		if v%2 != 0 {
+			return 0, errOdd
+		}
+		return v * 2, nil
+	}
+
+	tests := []struct {
+		name     string
+		in       []int
+		withErrc bool
+		want     []int
+		wantErrs int
+	}{
+		{name: "empty input", in: nil, withErrc: true, want: nil, wantErrs: 0},
+		{name: "errors sent to errc", in: []int{1, 2, 3, 4}, withErrc: true, want: []int{4, 8}, wantErrs: 2},
+		{name: "errors dropped with nil errc", in: []int{1, 2, 3, 4}, withErrc: false, want: []int{4, 8}, wantErrs: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errc chan error
+			if tt.withErrc {
+				errc = make(chan error, len(tt.in))
+			}
+
+			got := drain(t, Map(context.Background(), source(tt.in...), double, errc))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("outputs = %v, want %v", got, tt.want)
+			}
+
+			if len(errc) != tt.wantErrs {
+				t.Fatalf("errors = %d, want %d", len(errc), tt.wantErrs)
+			}
+			for i := 0; i < tt.wantErrs; i++ {
+				if err := <-errc; !errors.Is(err, errOdd) {
+					t.Errorf("error = %v, want %v", err, errOdd)
+				}
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		pred func(int) bool
+		want []int
+	}{
+		{name: "empty input", in: nil, pred: func(int) bool { return true }, want: nil},
+		{name: "keeps matches", in: []int{1, 2, 3, 4}, pred: func(v int) bool { return v%2 == 0 }, want: []int{2, 4}},
+		{name: "drops everything", in: []int{1, 2, 3}, pred: func(int) bool { return false }, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := drain(t, Filter(context.Background(), source(tt.in...), tt.pred))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("outputs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelClosesOutput(t *testing.T) {
+	identity := func(v int) (int, error) { return v, nil }
+
+	tests := []struct {
+		name   string
+		queued []int
+		run    func(ctx context.Context, in <-chan int) <-chan int
+	}{
+		{
+			name: "Map blocked on input",
+			run: func(ctx context.Context, in <-chan int) <-chan int {
+				return Map(ctx, in, identity, nil)
+			},
+		},
+		{
+			name:   "Map blocked on error send",
+			queued: []int{1},
+			run: func(ctx context.Context, in <-chan int) <-chan int {
+				return Map(ctx, in, func(int) (int, error) { return 0, errOdd }, make(chan error))
+			},
+		},
+		{
+			name: "Filter blocked on input",
+			run: func(ctx context.Context, in <-chan int) <-chan int {
+				return Filter(ctx, in, func(int) bool { return true })
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			// The input stays open, so only cancellation can close the output.
+			in := make(chan int, len(tt.queued))
+			for _, v := range tt.queued {
+				in <- v
+			}
+			out := tt.run(ctx, in)
+
+			cancel()
+
+			// The output is closed by a deferred close on the combinator's
+			// goroutine, so observing the close means the goroutine exited.
+			waitClosed(t, out)
+		})
+	}
+
+	t.Run("blocked on output send", func(t *testing.T) {
+		tests := []struct {
+			name string
+			run  func(ctx context.Context, in <-chan int) <-chan int
+		}{
+			{name: "Map", run: func(ctx context.Context, in <-chan int) <-chan int { return Map(ctx, in, identity, nil) }},
+			{name: "Filter", run: func(ctx context.Context, in <-chan int) <-chan int {
+				return Filter(ctx, in, func(int) bool { return true })
+			}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				out := tt.run(ctx, source(1, 2, 3))
+
+				// Give the goroutine time to block sending the first value
+				// before anyone reads.
+				time.Sleep(10 * time.Millisecond)
+				cancel()
+
+				waitClosed(t, out)
+			})
+		}
+	})
+}
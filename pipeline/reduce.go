@@ -0,0 +1,24 @@
+package pipeline
+
+import "context"
+
+// Reduce consumes in until it is closed, folding each value into an
+// accumulator that starts at initial, and returns the final accumulator.
+//
+// If ctx is cancelled first, Reduce stops reading and returns the value
+// accumulated so far together with ctx.Err().
+func Reduce[O, A any](ctx context.Context, in <-chan O, initial A, f func(A, O) A) (A, error) {
+	acc := initial
+
+	for {
+		select {
+		case <-ctx.Done():
+			return acc, ctx.Err()
+		case v, ok := <-in:
+			if !ok {
+				return acc, nil
+			}
+			acc = f(acc, v)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, v int) int { return acc + v }
+
+	tests := []struct {
+		name    string
+		in      []int
+		initial int
+		want    int
+	}{
+		{name: "empty input returns initial", in: nil, initial: 5, want: 5},
+		{name: "folds every value", in: []int{1, 2, 3, 4}, initial: 0, want: 10},
+		{name: "starts from initial", in: []int{1, 2}, initial: 10, want: 13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reduce(context.Background(), source(tt.in...), tt.initial, sum)
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if got != tt.want {
+				t.Errorf("result = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReduceCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The input stays open, so Reduce can only return on cancellation.
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+
+	type result struct {
+		acc int
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		acc, err := Reduce(ctx, in, 0, func(acc, v int) int { return acc + v })
+		done <- result{acc, err}
+	}()
+
+	// Let Reduce consume the queued values before cancelling.
+	for len(in) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case r := <-done:
+		if !errors.Is(r.err, context.Canceled) {
+			t.Errorf("err = %v, want %v", r.err, context.Canceled)
+		}
+		if r.acc != 3 {
+			t.Errorf("result = %d, want the partial accumulation 3", r.acc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Reduce did not return after cancel")
+	}
+}
@@ -0,0 +1,98 @@
+package pipeline
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Throttle returns a channel that receives the values read from in, passed
+// through no faster than r values per second with bursts of up to burst
+// values. The burst allowance starts full and refills continuously at rate
+// r.
+//
+// r follows the conventions of golang.org/x/time/rate.Limit, so a Limit
+// value can be passed as float64(limit): rate.Inf (math.MaxFloat64) or any
+// larger value, including +Inf, disables throttling, while zero or a
+// negative rate allows the initial burst and nothing after it. A NaN rate
+// is treated as zero. Unlike rate.Limiter, which admits nothing with a
+// burst of zero, a burst below one is treated as one.
+//
+// Throttle holds at most one value at a time: while it waits for the rate
+// limit or for the consumer it stops reading from in, so a rate-limited
+// stage pushes backpressure onto its producer rather than buffering. Give
+// in a buffer if the producer should be able to run ahead of the rate.
+func Throttle[T any](ctx context.Context, in <-chan T, r float64, burst int) <-chan T {
+	out := make(chan T)
+	burst = max(burst, 1)
+	unlimited := r >= math.MaxFloat64
+	if r < 0 || math.IsNaN(r) {
+		r = 0
+	}
+
+	go func() {
+		defer close(out)
+
+		tokens := float64(burst)
+		last := time.Now()
+
+		// take blocks until a token is available and consumes it. It
+		// reports false if ctx is cancelled while waiting.
+		take := func() bool {
+			for {
+				now := time.Now()
+				tokens = math.Min(float64(burst), tokens+now.Sub(last).Seconds()*r)
+				last = now
+
+				if tokens >= 1 {
+					tokens--
+					return true
+				}
+
+				// With no refill, or a wait too long to represent as a
+				// Duration, the next token never arrives; only ctx can end
+				// the wait.
+				var timer *time.Timer
+				var refilled <-chan time.Time
+				if r > 0 {
+					if wait := (1 - tokens) / r * float64(time.Second); wait < math.MaxInt64 {
+						timer = time.NewTimer(time.Duration(wait))
+						refilled = timer.C
+					}
+				}
+
+				select {
+				case <-ctx.Done():
+					if timer != nil {
+						timer.Stop()
+					}
+					return false
+				case <-refilled:
+				}
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				if !unlimited && !take() {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case out <- v:
+				}
+			}
+		}
+	}()
+
+	return out
+}
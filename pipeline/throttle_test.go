@@ -0,0 +1,123 @@
+package pipeline
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []int
+		r       float64
+		burst   int
+		minTime time.Duration
+	}{
+		{name: "burst passes immediately", in: []int{1, 2, 3}, r: 1, burst: 3, minTime: 0},
+		{name: "rate spaces values beyond the burst", in: []int{1, 2, 3, 4}, r: 50, burst: 1, minTime: 55 * time.Millisecond},
+		{name: "non-positive burst acts as one", in: []int{1, 2, 3}, r: 50, burst: 0, minTime: 35 * time.Millisecond},
+		{name: "zero rate passes the burst", in: []int{1, 2, 3}, r: 0, burst: 3, minTime: 0},
+		{name: "max float rate is unthrottled", in: []int{1, 2, 3}, r: math.MaxFloat64, burst: 1, minTime: 0},
+		{name: "infinite rate is unthrottled", in: []int{1, 2, 3}, r: math.Inf(1), burst: 1, minTime: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			got := drain(t, Throttle(context.Background(), source(tt.in...), tt.r, tt.burst))
+			elapsed := time.Since(start)
+
+			if !slices.Equal(got, tt.in) {
+				t.Errorf("outputs = %v, want %v", got, tt.in)
+			}
+			if elapsed < tt.minTime {
+				t.Errorf("took %v, want at least %v", elapsed, tt.minTime)
+			}
+			if tt.minTime == 0 && elapsed > 500*time.Millisecond {
+				t.Errorf("took %v, want values to pass without waiting", elapsed)
+			}
+		})
+	}
+}
+
+func TestThrottleCancelWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// One value per hour: the second value waits on the rate limit.
+	out := Throttle(ctx, source(1, 2), 1.0/3600, 1)
+	if v := <-out; v != 1 {
+		t.Fatalf("first value = %d, want 1", v)
+	}
+
+	cancel()
+	waitClosed(t, out)
+}
+
+func TestThrottleNoRefill(t *testing.T) {
+	tests := []struct {
+		name string
+		r    float64
+	}{
+		{name: "zero rate", r: 0},
+		{name: "negative rate", r: -1},
+		{name: "NaN rate", r: math.NaN()},
+		{name: "tiny rate", r: 1e-11},
+		{name: "smallest rate", r: math.SmallestNonzeroFloat64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The input stays open, so only cancellation can close the output.
+			in := make(chan int, 2)
+			in <- 1
+			in <- 2
+			out := Throttle(ctx, in, tt.r, 1)
+
+			if v := receiveValue(t, out); v != 1 {
+				t.Fatalf("first value = %d, want 1 from the burst", v)
+			}
+
+			// The second value waits on a token that never arrives. The
+			// wait must block rather than spin, so allocations stay low.
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			select {
+			case v := <-out:
+				t.Fatalf("value %d passed after the burst was spent", v)
+			case <-time.After(50 * time.Millisecond):
+			}
+			runtime.ReadMemStats(&after)
+			if allocs := after.Mallocs - before.Mallocs; allocs > 10000 {
+				t.Errorf("%d allocations while waiting, want the goroutine blocked", allocs)
+			}
+
+			cancel()
+			waitClosed(t, out)
+		})
+	}
+}
+
+// receiveValue returns the next value from out, failing the test if none
+// arrives within a second.
+func receiveValue(t *testing.T, out <-chan int) int {
+	t.Helper()
+
+	select {
+	case v, ok := <-out:
+		if !ok {
+			t.Fatal("channel closed, want a value")
+		}
+		return v
+	case <-time.After(time.Second):
+		t.Fatal("no value received")
+	}
+
+	return 0
+}
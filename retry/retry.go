@@ -0,0 +1,77 @@
+// Package retry runs an operation repeatedly until it succeeds, its attempts
+// are exhausted, it fails with an error that should not be retried, or its
+// context is cancelled.
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Policy describes how an operation is retried. The zero value runs the
+// operation once.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below one are treated as one.
+	MaxAttempts int
+
+	// Backoff returns how long to wait after the given failed attempt,
+	// counting from one. A nil Backoff retries immediately.
+	Backoff func(attempt int) time.Duration
+
+	// Retryable reports whether a failed attempt should be retried. A nil
+	// Retryable retries every error.
+	Retryable func(err error) bool
+}
+
+// Do calls fn until it returns nil or the policy gives up, and returns the
+// last error fn returned.
+//
+// If ctx is cancelled before an attempt or while waiting between attempts,
+// Do returns the last error from fn joined with ctx.Err(), so both can be
+// matched with errors.Is.
+func Do(ctx context.Context, fn func() error, policy Policy) error {
+	_, err := DoValue(ctx, func() (struct{}, error) {
+		return struct{}{}, fn()
+	}, policy)
+
+	return err
+}
+
+// DoValue is like Do for operations that produce a value. It returns the
+// value from the successful attempt, or the zero value and the last error.
+func DoValue[T any](ctx context.Context, fn func() (T, error), policy Policy) (T, error) {
+	var zero T
+
+	attempts := max(policy.MaxAttempts, 1)
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, errors.Join(lastErr, err)
+		}
+
+		v, err := fn()
+		if err == nil {
+			return v, nil
+		}
+		lastErr = err
+
+		if attempt >= attempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+			return zero, lastErr
+		}
+
+		if policy.Backoff == nil {
+			continue
+		}
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, errors.Join(lastErr, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFail = errors.New("fail")
+
+func TestDoAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		want        int
+	}{
+		{name: "negative runs once", maxAttempts: -1, want: 1},
+		{name: "zero runs once", maxAttempts: 0, want: 1},
+		{name: "one runs once", maxAttempts: 1, want: 1},
+		{name: "runs every attempt", maxAttempts: 4, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), func() error {
+				calls++
+				return errFail
+			}, Policy{MaxAttempts: tt.maxAttempts})
+
+			if !errors.Is(err, errFail) {
+				t.Errorf("err = %v, want %v", err, errFail)
+			}
+			if calls != tt.want {
+				t.Errorf("calls = %d, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoStopsOnSuccess(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return errFail
+		}
+		return nil
+	}, Policy{MaxAttempts: 5})
+
+	if err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestDoNotRetryable(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func() error {
+		calls++
+		return errFail
+	}, Policy{
+		MaxAttempts: 5,
+		Retryable:   func(error) bool { return false },
+	})
+
+	if !errors.Is(err, errFail) {
+		t.Errorf("err = %v, want %v", err, errFail)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDoBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff func(int) time.Duration
+		minTime time.Duration
+		maxTime time.Duration
+	}{
+		{name: "nil backoff retries immediately", backoff: nil, minTime: 0, maxTime: 50 * time.Millisecond},
+		{name: "waits between attempts", backoff: func(int) time.Duration { return 10 * time.Millisecond }, minTime: 20 * time.Millisecond, maxTime: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts []int
+			policy := Policy{MaxAttempts: 3}
+			if tt.backoff != nil {
+				policy.Backoff = func(attempt int) time.Duration {
+					attempts = append(attempts, attempt)
+					return tt.backoff(attempt)
+				}
+			}
+
+			start := time.Now()
+			_ = Do(context.Background(), func() error { return errFail }, policy)
+			elapsed := time.Since(start)
+
+			if elapsed < tt.minTime || elapsed > tt.maxTime {
+				t.Errorf("took %v, want between %v and %v", elapsed, tt.minTime, tt.maxTime)
+			}
+			if tt.backoff != nil && (len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2) {
+				t.Errorf("backoff attempts = %v, want [1 2]", attempts)
+			}
+		})
+	}
+}
+
+func TestDoCancelDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := Do(ctx, func() error {
+		calls++
+		return errFail
+	}, Policy{
+		MaxAttempts: 5,
+		Backoff: func(int) time.Duration {
+			cancel()
+			return time.Hour
+		},
+	})
+
+	if !errors.Is(err, errFail) {
+		t.Errorf("err = %v, want it to wrap %v", err, errFail)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want it to wrap %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestDoCancelledBeforeFirstAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := Do(ctx, func() error {
+		calls++
+		return nil
+	}, Policy{MaxAttempts: 3})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+	if calls != 0 {
+		t.Errorf("calls = %d, want 0", calls)
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	v, err := DoValue(context.Background(), func() (string, error) {
+		calls++
+		if calls < 2 {
+			return "partial", errFail
+		}
+		return "done", nil
+	}, Policy{MaxAttempts: 3})
+
+	if err != nil || v != "done" {
+		t.Errorf("DoValue = (%q, %v), want (\"done\", nil)", v, err)
+	}
+
+	v, err = DoValue(context.Background(), func() (string, error) {
+		return "partial", errFail
+	}, Policy{MaxAttempts: 2})
+
+	if !errors.Is(err, errFail) || v != "" {
+		t.Errorf("DoValue = (%q, %v), want the zero value and %v", v, err, errFail)
+	}
+}